@@ -0,0 +1,113 @@
+// Package cli provides shared cobra/viper wiring used by scope's root command.
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	homedir "github.com/mitchellh/go-homedir"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+var cfgFile string
+
+// PrepareBaseCmd wires env var and config file binding into root, so that every
+// flag defined anywhere in root's command tree can also be set via a
+// "<envPrefix>_FLAG_NAME" environment variable or a config file, with
+// precedence flag > env > config > default. defaultCfgFile is only shown in
+// the --config usage string; when --config isn't passed, the config file is
+// looked up as "config" (with any extension viper reads) in ~/.scope.
+func PrepareBaseCmd(root *cobra.Command, envPrefix, defaultCfgFile string) {
+	// Cobra only runs the first non-nil PersistentPreRunE it finds walking up
+	// from the invoked command to root. Subcommands in this tree such as run
+	// set their own PersistentPreRunE (flagrules.Register, metric-format
+	// validation, ...), which would otherwise shadow this binder entirely.
+	// EnableTraverseRunHooks makes cobra run every level's hook, root to leaf.
+	cobra.EnableTraverseRunHooks = true
+
+	root.PersistentFlags().StringVar(&cfgFile, "config", "", fmt.Sprintf("config file (default %s)", defaultCfgFile))
+
+	cobra.OnInitialize(func() { initConfig(defaultCfgFile) })
+
+	existingPreRunE := root.PersistentPreRunE
+	root.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		if err := BindFlags(cmd.Flags(), envPrefix); err != nil {
+			return err
+		}
+		if existingPreRunE != nil {
+			return existingPreRunE(cmd, args)
+		}
+		return nil
+	}
+}
+
+// initConfig reads in a config file and ENV variables if set.
+func initConfig(defaultCfgFile string) {
+	if cfgFile != "" {
+		viper.SetConfigFile(cfgFile)
+	} else {
+		home, err := homedir.Dir()
+		if err != nil {
+			return
+		}
+		viper.AddConfigPath(home + "/.scope")
+		viper.SetConfigName("config")
+	}
+
+	if err := viper.ReadInConfig(); err != nil {
+		if _, ok := err.(viper.ConfigFileNotFoundError); !ok {
+			fmt.Fprintf(os.Stderr, "error reading config file: %v\n", err)
+		}
+	}
+}
+
+// BindFlags binds every flag in set to a viper key of the same name, an env
+// var of "<envPrefix>_<FLAG_NAME>", and - unless the flag was set explicitly
+// on the command line - overwrites its value with whatever viper resolved
+// from env or config. It's exported so tools that introspect a command
+// without ever executing it (e.g. `scope flags`, reporting on every
+// subcommand's flags) can bind a FlagSet themselves instead of only getting
+// this for free via PrepareBaseCmd's PersistentPreRunE.
+func BindFlags(set *pflag.FlagSet, envPrefix string) error {
+	v := viper.GetViper()
+	var bindErr error
+	set.VisitAll(func(f *pflag.Flag) {
+		if bindErr != nil {
+			return
+		}
+		envVar := envPrefix + "_" + strings.ToUpper(strings.ReplaceAll(f.Name, "-", "_"))
+		if err := v.BindEnv(f.Name, envVar); err != nil {
+			bindErr = err
+			return
+		}
+		if err := v.BindPFlag(f.Name, f); err != nil {
+			bindErr = err
+			return
+		}
+		if !f.Changed && v.IsSet(f.Name) {
+			bindErr = set.Set(f.Name, flagString(v.Get(f.Name)))
+		}
+	})
+	return bindErr
+}
+
+// flagString renders a viper value as the string pflag.Value.Set expects.
+// Most flag types round-trip fine through fmt.Sprintf("%v", ...), but a
+// StringToString flag (e.g. --metricheaders/--metricresource) parses
+// "k=v,k2=v2", not Go's map-printing syntax - and a map value read back from
+// a YAML config file comes out of viper as map[string]interface{}, not
+// map[string]string, so it wouldn't even match a type switch on the latter.
+func flagString(val interface{}) string {
+	m, ok := val.(map[string]interface{})
+	if !ok {
+		return fmt.Sprintf("%v", val)
+	}
+	pairs := make([]string, 0, len(m))
+	for k, v := range m {
+		pairs = append(pairs, fmt.Sprintf("%s=%v", k, v))
+	}
+	return strings.Join(pairs, ",")
+}
@@ -0,0 +1,60 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+func TestFlagStringScalar(t *testing.T) {
+	if got := flagString("statsd"); got != "statsd" {
+		t.Fatalf("flagString(%q) = %q, want %q", "statsd", got, "statsd")
+	}
+}
+
+func TestFlagStringMap(t *testing.T) {
+	got := flagString(map[string]interface{}{"k": "v"})
+	if got != "k=v" {
+		t.Fatalf("flagString(map) = %q, want %q", got, "k=v")
+	}
+}
+
+func TestBindFlagsAppliesEnvVarOverDefault(t *testing.T) {
+	viper.Reset()
+	defer viper.Reset()
+
+	t.Setenv("TESTPREFIX_GREETING", "hello")
+
+	set := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	var greeting string
+	set.StringVar(&greeting, "greeting", "default", "")
+
+	if err := BindFlags(set, "TESTPREFIX"); err != nil {
+		t.Fatalf("BindFlags returned %v", err)
+	}
+	if greeting != "hello" {
+		t.Fatalf("greeting = %q, want %q", greeting, "hello")
+	}
+}
+
+func TestBindFlagsLeavesExplicitlySetFlagsAlone(t *testing.T) {
+	viper.Reset()
+	defer viper.Reset()
+
+	t.Setenv("TESTPREFIX_GREETING", "hello")
+
+	set := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	var greeting string
+	set.StringVar(&greeting, "greeting", "default", "")
+	if err := set.Set("greeting", "explicit"); err != nil {
+		t.Fatalf("Set returned %v", err)
+	}
+
+	if err := BindFlags(set, "TESTPREFIX"); err != nil {
+		t.Fatalf("BindFlags returned %v", err)
+	}
+	if greeting != "explicit" {
+		t.Fatalf("greeting = %q, want %q", greeting, "explicit")
+	}
+}
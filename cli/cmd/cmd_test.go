@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"context"
+	"testing"
+
+	"github.com/criblio/scope/history"
+	"github.com/criblio/scope/render"
+	"github.com/spf13/cobra"
+)
+
+func TestOutputTypeDefaultsToText(t *testing.T) {
+	orig := outputFlag
+	defer func() { outputFlag = orig }()
+
+	outputFlag = ""
+	if got := OutputType(&cobra.Command{}); got != render.Text {
+		t.Fatalf("OutputType() = %q, want %q", got, render.Text)
+	}
+}
+
+func TestOutputTypeRecognizesJSONAndYAML(t *testing.T) {
+	orig := outputFlag
+	defer func() { outputFlag = orig }()
+
+	outputFlag = string(render.JSON)
+	if got := OutputType(&cobra.Command{}); got != render.JSON {
+		t.Fatalf("OutputType() = %q, want %q", got, render.JSON)
+	}
+
+	outputFlag = string(render.YAML)
+	if got := OutputType(&cobra.Command{}); got != render.YAML {
+		t.Fatalf("OutputType() = %q, want %q", got, render.YAML)
+	}
+}
+
+func TestRootOptsFromReturnsStashedOpts(t *testing.T) {
+	opts := &rootOpts{Store: history.NewFsStore(t.TempDir())}
+	cmd := &cobra.Command{}
+	cmd.SetContext(withRootOpts(context.Background(), opts))
+
+	if got := rootOptsFrom(cmd); got != opts {
+		t.Fatalf("rootOptsFrom() = %v, want %v", got, opts)
+	}
+}
+
+func TestRootOptsFromPanicsWithoutContext(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected rootOptsFrom to panic when rootOpts is missing from context")
+		}
+	}()
+	rootOptsFrom(&cobra.Command{})
+}
+
+func TestFlagInfoString(t *testing.T) {
+	f := flagInfo{
+		Command:      "run",
+		Name:         "cribldest",
+		Shorthand:    "c",
+		Default:      "",
+		Value:        "localhost:10090",
+		Source:       "flag",
+		Incompatible: []string{"mutually exclusive with --eventdest"},
+	}
+	want := "run --cribldest (-c)\tdefault=\tvalue=localhost:10090\tsource=flag\tmutually exclusive with --eventdest"
+	if got := f.String(); got != want {
+		t.Fatalf("flagInfo.String() = %q, want %q", got, want)
+	}
+}
+
+func TestFlagInfoListString(t *testing.T) {
+	fl := flagInfoList{
+		{Command: "scope", Name: "output", Default: "text", Value: "text", Source: "default"},
+		{Command: "run", Name: "verbosity", Default: "4", Value: "4", Source: "default"},
+	}
+	want := fl[0].String() + "\n" + fl[1].String()
+	if got := fl.String(); got != want {
+		t.Fatalf("flagInfoList.String() = %q, want %q", got, want)
+	}
+}
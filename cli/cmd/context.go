@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"context"
+	"io"
+	"log"
+
+	"github.com/criblio/scope/history"
+	"github.com/criblio/scope/run"
+	"github.com/spf13/cobra"
+)
+
+// rootOpts bundles the dependencies subcommands need. It's constructed once
+// in root command setup (see root.go) and threaded through
+// cobra.Command.Context() rather than read off package globals, so commands
+// stay unit-testable with fake stores/runners and can run in parallel.
+type rootOpts struct {
+	Store  history.Store
+	Runner run.Runner
+	Prompt io.Writer
+	Log    *log.Logger
+}
+
+type rootOptsKey struct{}
+
+// withRootOpts returns a copy of ctx carrying opts.
+func withRootOpts(ctx context.Context, opts *rootOpts) context.Context {
+	return context.WithValue(ctx, rootOptsKey{}, opts)
+}
+
+// rootOptsFrom retrieves the rootOpts stashed on cmd's context by root.go.
+// It panics if called before Execute has set up the root context, which
+// would indicate a programming error rather than bad user input.
+func rootOptsFrom(cmd *cobra.Command) *rootOpts {
+	opts, ok := cmd.Context().Value(rootOptsKey{}).(*rootOpts)
+	if !ok {
+		panic("cmd: rootOpts missing from context; was Execute used to run this command?")
+	}
+	return opts
+}
@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	gocli "github.com/criblio/scope/cli"
+	"github.com/criblio/scope/flagrules"
+	"github.com/criblio/scope/render"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// flagInfo is the machine-readable shape of one row of `scope flags` output.
+type flagInfo struct {
+	Command      string   `json:"command" yaml:"command"`
+	Name         string   `json:"name" yaml:"name"`
+	Shorthand    string   `json:"shorthand,omitempty" yaml:"shorthand,omitempty"`
+	Default      string   `json:"default" yaml:"default"`
+	Value        string   `json:"value" yaml:"value"`
+	Source       string   `json:"source" yaml:"source"`
+	Incompatible []string `json:"incompatible,omitempty" yaml:"incompatible,omitempty"`
+}
+
+func (f flagInfo) String() string {
+	line := f.Command + " --" + f.Name
+	if f.Shorthand != "" {
+		line += " (-" + f.Shorthand + ")"
+	}
+	line += fmt.Sprintf("\tdefault=%s\tvalue=%s\tsource=%s", f.Default, f.Value, f.Source)
+	if len(f.Incompatible) > 0 {
+		line += "\t" + strings.Join(f.Incompatible, "; ")
+	}
+	return line
+}
+
+// flagInfoList is a render.Object-able list of flagInfo rows.
+type flagInfoList []flagInfo
+
+func (fl flagInfoList) String() string {
+	lines := make([]string, len(fl))
+	for i, f := range fl {
+		lines[i] = f.String()
+	}
+	return strings.Join(lines, "\n")
+}
+
+var flagsCmd = &cobra.Command{
+	Use:   "flags",
+	Short: "List every scope flag and its effective value",
+	Long: `Flags walks the root command's persistent flags plus every subcommand's
+own flags and reports each one's owning command, name, shorthand, default,
+current effective value, where that value came from (default/config/env/flag),
+and any incompatibility rules registered against it.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		var list flagInfoList
+
+		addFlags := func(owner *cobra.Command, set *pflag.FlagSet) {
+			// Only the command cobra is actually executing (flags itself)
+			// gets its flags bound to viper by cli.PrepareBaseCmd's
+			// PersistentPreRunE; bind every other listed command's flags
+			// here too, so their reported value/source reflect env/config
+			// even though that command never ran. This also sidesteps
+			// SourceOf's need for owner.Flags() to already be merged with
+			// persistent flags, which is only true for the executed command.
+			if err := gocli.BindFlags(set, "SCOPE"); err != nil {
+				helpErrAndExit(cmd, fmt.Sprintf("error binding flags for %s: %v", owner.Name(), err))
+			}
+			set.VisitAll(func(f *pflag.Flag) {
+				list = append(list, flagInfo{
+					Command:      owner.Name(),
+					Name:         f.Name,
+					Shorthand:    f.Shorthand,
+					Default:      f.DefValue,
+					Value:        f.Value.String(),
+					Source:       flagrules.SourceOfFlag(f, "SCOPE"),
+					Incompatible: flagrules.DescribeFlag(owner, f.Name),
+				})
+			})
+		}
+
+		addFlags(rootCmd, rootCmd.PersistentFlags())
+		for _, sub := range rootCmd.Commands() {
+			if sub == cmd {
+				continue
+			}
+			// LocalFlags, not Flags: Flags() would re-include the persistent
+			// flags already listed above under rootCmd, duplicating rows.
+			addFlags(sub, sub.LocalFlags())
+		}
+
+		render.Object(os.Stdout, OutputType(cmd), list)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(flagsCmd)
+}
@@ -0,0 +1,30 @@
+package cmd
+
+import (
+	"github.com/criblio/scope/flagrules"
+	"github.com/criblio/scope/render"
+	"github.com/spf13/cobra"
+)
+
+var outputFlag string
+var yesFlag, noFlag bool
+
+func init() {
+	rootCmd.PersistentFlags().StringVarP(&outputFlag, "output", "o", string(render.Text), "Set output format (text|json|yaml)")
+	rootCmd.PersistentFlags().BoolVar(&yesFlag, "yes", false, "Answer yes to any interactive prompts, for non-interactive use")
+	rootCmd.PersistentFlags().BoolVar(&noFlag, "no", false, "Answer no to any interactive prompts, for non-interactive use")
+	flagrules.Register(rootCmd, "SCOPE", flagrules.MutuallyExclusive("yes", "no"))
+}
+
+// OutputType returns the render.Format selected via --output (or its bound
+// env var/config value; see cli.PrepareBaseCmd), defaulting to render.Text.
+func OutputType(cmd *cobra.Command) render.Format {
+	switch render.Format(outputFlag) {
+	case render.JSON:
+		return render.JSON
+	case render.YAML:
+		return render.YAML
+	default:
+		return render.Text
+	}
+}
@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	gocli "github.com/criblio/scope/cli"
+	"github.com/criblio/scope/history"
+	"github.com/criblio/scope/run"
+	"github.com/spf13/cobra"
+)
+
+// defaultConfigFile is where scope looks for a config file when --config isn't set.
+const defaultConfigFile = "~/.scope/config.yaml"
+
+var rootCmd = &cobra.Command{
+	Use:   "scope",
+	Short: "Scope is a command line utility to gain visibility into your applications",
+}
+
+func init() {
+	gocli.PrepareBaseCmd(rootCmd, "SCOPE", defaultConfigFile)
+}
+
+// newRootOpts builds the dependencies every subcommand needs.
+func newRootOpts() *rootOpts {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		home = "."
+	}
+	return &rootOpts{
+		Store:  history.NewFsStore(filepath.Join(home, ".scope", "history")),
+		Runner: run.NewRunner(),
+		Prompt: os.Stdout,
+		Log:    log.New(os.Stderr, "", log.LstdFlags),
+	}
+}
+
+// Execute runs the root scope command, exiting the process on error.
+func Execute() {
+	rootCmd.SetContext(withRootOpts(context.Background(), newRootOpts()))
+	if err := rootCmd.Execute(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
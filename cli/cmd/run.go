@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"github.com/criblio/scope/flagrules"
+	"github.com/criblio/scope/run"
+	"github.com/spf13/cobra"
+)
+
+var runCfg run.Config
+
+var runCmd = &cobra.Command{
+	Use:   "run",
+	Short: "Executes a scoped command",
+	Long:  `Run executes a scoped command, configured by the flags or environment passed to this command.`,
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		opts := rootOptsFrom(cmd)
+		if err := opts.Runner.Run(&runCfg, args); err != nil {
+			errAndExit(cmd, "error running %v: %v", args, err)
+		}
+	},
+}
+
+func init() {
+	runCmdFlags(runCmd, &runCfg)
+	flagrules.Register(runCmd, "SCOPE", runFlagRules...)
+
+	existing := runCmd.PersistentPreRunE
+	runCmd.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		if existing != nil {
+			if err := existing(cmd, args); err != nil {
+				return err
+			}
+		}
+		return runCfg.ValidateMetricFormat(metricFormats)
+	}
+
+	rootCmd.AddCommand(runCmd)
+}
@@ -3,55 +3,108 @@ package cmd
 import (
 	"fmt"
 	"os"
-	"strings"
 
+	"github.com/criblio/scope/flagrules"
 	"github.com/criblio/scope/history"
+	"github.com/criblio/scope/render"
 	"github.com/criblio/scope/run"
+	"github.com/criblio/scope/run/metricfmt"
 	"github.com/criblio/scope/util"
 	"github.com/spf13/cobra"
 )
 
 // sessionByID returns a session by ID, or if -1 (not set) returns last session
-func sessionByID(id int) history.SessionList {
+func sessionByID(cmd *cobra.Command, id int) history.SessionList {
+	store := rootOptsFrom(cmd).Store
 	var sessions history.SessionList
 	if id == -1 {
-		sessions = history.GetSessions().Last(1)
+		sessions = store.Sessions().Last(1)
 	} else {
-		sessions = history.GetSessions().ID(id)
+		sessions = store.Sessions().ID(id)
 	}
 	sessionCount := len(sessions)
 	if sessionCount != 1 {
-		util.ErrAndExit("error expected a single session, saw: %d", sessionCount)
+		errAndExit(cmd, "error expected a single session, saw: %d", sessionCount)
 	}
 	return sessions
 }
 
-func promptClean(sl history.SessionList) {
-	fmt.Print("Invalid session, likely an invalid command was scoped or a session file was modified. Would you like to delete this session? (default: yes) [y/n] ")
-	var response string
-	_, err := fmt.Scanf("%s", &response)
-	util.CheckErrSprintf(err, "error reading response: %v", err)
-	if !(response == "n" || response == "no") {
+// promptClean asks whether to delete an invalid session, unless --yes/--no or
+// a non-text --output mode already answered the question: --yes/--no decide
+// it outright, and outside of text mode there's no interactive prompt to show
+// so it defaults to removing the session and reports the outcome as an
+// object instead.
+func promptClean(cmd *cobra.Command, sl history.SessionList) {
+	opts := rootOptsFrom(cmd)
+	format := OutputType(cmd)
+
+	remove := true
+	switch {
+	case noFlag:
+		remove = false
+	case yesFlag, format != render.Text:
+		remove = true
+	default:
+		fmt.Fprint(opts.Prompt, "Invalid session, likely an invalid command was scoped or a session file was modified. Would you like to delete this session? (default: yes) [y/n] ")
+		var response string
+		_, err := fmt.Scanf("%s", &response)
+		util.CheckErrSprintf(err, "error reading response: %v", err)
+		remove = !(response == "n" || response == "no")
+	}
+
+	if remove {
 		sl.Remove()
 	}
+	if format != render.Text {
+		render.Object(opts.Prompt, format, render.PromptResult{Removed: remove})
+	}
 	os.Exit(0)
 }
 
 func helpErrAndExit(cmd *cobra.Command, errText string) {
+	if format := OutputType(cmd); format != render.Text {
+		render.Object(os.Stderr, format, render.ErrorResult{Error: errText})
+		os.Exit(1)
+	}
 	cmd.Help()
 	fmt.Printf("\nerror: %s\n", errText)
 	os.Exit(1)
 }
 
+// errAndExit exits with an error built from format/args, emitting a
+// render.ErrorResult when --output isn't text (as helpErrAndExit does)
+// instead of util.ErrAndExit's always-plain-text stderr line.
+func errAndExit(cmd *cobra.Command, format string, args ...interface{}) {
+	errText := fmt.Sprintf(format, args...)
+	if f := OutputType(cmd); f != render.Text {
+		render.Object(os.Stderr, f, render.ErrorResult{Error: errText})
+		os.Exit(1)
+	}
+	util.ErrAndExit("%s", errText)
+}
+
+// metricFormats is the registry of --metricformat values scope accepts; see
+// the metricfmt package for how to add a new one.
+var metricFormats = metricfmt.NewRegistry()
+
+// metricAndEventDestFlags defines the metric/event destination flags shared by
+// several commands. Every flag it defines is automatically bindable from a
+// "SCOPE_"-prefixed environment variable and from ~/.scope/config.yaml (or
+// --config), via cli.PrepareBaseCmd's PersistentPreRunE; flag > env > config > default.
 func metricAndEventDestFlags(cmd *cobra.Command, rc *run.Config) {
 	cmd.Flags().StringVarP(&rc.CriblDest, "cribldest", "c", "", "Set Cribl destination for metrics & events (host:port defaults to tls://)")
-	cmd.Flags().StringVar(&rc.MetricsFormat, "metricformat", "ndjson", "Set format of metrics output (statsd|ndjson)")
-	cmd.Flags().StringVarP(&rc.MetricsDest, "metricdest", "m", "", "Set destination for metrics (host:port defaults to tls://)")
+	cmd.Flags().StringVar(&rc.MetricsFormat, "metricformat", "ndjson", fmt.Sprintf("Set format of metrics output (%s)", metricFormats.Usage()))
+	cmd.Flags().StringVarP(&rc.MetricsDest, "metricdest", "m", "", "Set destination for metrics (host:port defaults to tls://, or the transport implied by --metricformat)")
 	cmd.Flags().StringVarP(&rc.EventsDest, "eventdest", "e", "", "Set destination for events (host:port defaults to tls://)")
 	cmd.Flags().BoolVarP(&rc.NoBreaker, "nobreaker", "n", false, "Set Cribl to not break streams into events.")
 	cmd.Flags().StringVarP(&rc.AuthToken, "authtoken", "a", "", "Set AuthToken for Cribl")
+	cmd.Flags().StringToStringVar(&rc.MetricHeaders, "metricheaders", nil, "Set extra headers (k=v) sent with metric exports; only valid for OTLP --metricformat values")
+	cmd.Flags().StringToStringVar(&rc.MetricResource, "metricresource", nil, "Set OTLP resource attributes (k=v) attached to metric exports; only valid for OTLP --metricformat values")
 }
 
+// runCmdFlags defines the flags accepted by `scope run`. Like
+// metricAndEventDestFlags, each flag is also bindable from the environment and
+// from a config file; see cli.PrepareBaseCmd.
 func runCmdFlags(cmd *cobra.Command, rc *run.Config) {
 	cmd.Flags().BoolVar(&rc.Passthrough, "passthrough", false, "Runs ldscope with current environment & no config.")
 	cmd.Flags().IntVarP(&rc.Verbosity, "verbosity", "v", 4, "Set scope metric verbosity")
@@ -62,60 +115,15 @@ func runCmdFlags(cmd *cobra.Command, rc *run.Config) {
 	metricAndEventDestFlags(cmd, rc)
 }
 
-/*
-Incompatible flags list, key not present = no icompatibilities, key = nil exclusive flag, key = map incompatible flag list
---cribldest && --eventdest
---cribldest && --metricsdest
---userconfig && (--metricsdest || --eventsdest || --cribldest || --loglevel ....etc)
---help && [anything else]
---passthrough && [anything else]
-*/
-var IncompatibleFlags = map[string]map[string]int{
-	"cribldest": {
-		"eventdest":   1,
-		"metricsdest": 1,
-	},
-	"metricsdest": {
-		"cribldest": 1,
-	},
-	"eventdest": {
-		"cribldest": 1,
-	},
-	"userconfig": {
-		"metricsdest": 1,
-		"eventsdest":  1,
-		"cribldest":   1,
-		"loglevel":    1,
-	},
-	"passthrough": nil,
-	"help":        nil,
-}
-
-func checkIncompatibleFlags(flags []string) error {
-	for _, fl := range flags {
-		incompatible, exists := IncompatibleFlags[fl]
-		if !exists {
-			continue
-		}
-		if incompatible == nil {
-			return fmt.Errorf("Flag \"%s\" can't be used with other flags", fl)
-		}
-		for _, nextFl := range flags {
-			_, exist := incompatible[nextFl]
-			if exist {
-				return fmt.Errorf("Flag \"%s\" can't be used with \"%s\"", fl, nextFl)
-			}
-		}
-	}
-	return nil
-}
-
-func getFlags(args []string) []string {
-	rv := make([]string, len(args))
-	for _, arg := range args {
-		if strings.HasPrefix(arg, "--") {
-			rv = append(rv, strings.Replace(arg, "--", "", 1))
-		}
-	}
-	return rv
+// runFlagRules are the flagrules.Rule values that used to live in the
+// IncompatibleFlags map, plus the --metricformat=statsd/--nobreaker rule; see
+// flagrules.Register, called from run.go once runCmdFlags has defined these
+// flags on the command.
+var runFlagRules = []flagrules.Rule{
+	flagrules.MutuallyExclusive("cribldest", "eventdest"),
+	flagrules.MutuallyExclusive("cribldest", "metricdest"),
+	flagrules.Forbids("userconfig", "metricdest", "eventdest", "cribldest", "loglevel"),
+	flagrules.Exclusive("passthrough"),
+	flagrules.Exclusive("help"),
+	flagrules.WhenEquals("metricformat", "statsd").Forbids("nobreaker"),
 }
@@ -0,0 +1,335 @@
+// Package flagrules provides a declarative way to describe which flags on a
+// cobra command may, or must, be used together. It replaces the old
+// hand-rolled IncompatibleFlags map and checkIncompatibleFlags function,
+// which only expressed "these two flags can't both be set" and scaled as
+// O(n^2) string lookups.
+package flagrules
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// Rule is one constraint on how a command's flags may be combined.
+type Rule interface {
+	check(cmd *cobra.Command, envPrefix string) error
+	names() []string
+	// describe renders this rule from flag's point of view, for `scope flags`.
+	describe(flag string) string
+}
+
+// Register attaches rules to cmd, validates them in a shared
+// PersistentPreRunE (chaining any PersistentPreRunE already set on cmd), and
+// registers a value-completion func for every flag a rule mentions. That func
+// suggests no values, and reports a completion error when the flags already
+// on the command line break one of cmd's rules; the list of flag names shell
+// completion suggests is not filtered. envPrefix is used only to describe a flag's source
+// (flag/env/config) in error messages; pass the same prefix given to
+// cli.PrepareBaseCmd.
+func Register(cmd *cobra.Command, envPrefix string, rules ...Rule) {
+	byCmd[cmd] = append(byCmd[cmd], rules...)
+
+	existing := cmd.PersistentPreRunE
+	cmd.PersistentPreRunE = func(c *cobra.Command, args []string) error {
+		if err := Validate(cmd, envPrefix); err != nil {
+			return err
+		}
+		if existing != nil {
+			return existing(c, args)
+		}
+		return nil
+	}
+
+	seen := map[string]bool{}
+	for _, r := range rules {
+		for _, name := range r.names() {
+			if seen[name] {
+				continue
+			}
+			seen[name] = true
+			name := name
+			cmd.RegisterFlagCompletionFunc(name, func(c *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+				if err := Validate(cmd, envPrefix); err != nil {
+					return nil, cobra.ShellCompDirectiveError
+				}
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			})
+		}
+	}
+}
+
+// Validate runs every rule registered against cmd, returning the first
+// violation found.
+func Validate(cmd *cobra.Command, envPrefix string) error {
+	for _, r := range byCmd[cmd] {
+		if err := r.check(cmd, envPrefix); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DescribeFlag returns a human-readable description of every rule registered
+// against cmd that mentions flag, for `scope flags` to report alongside each
+// flag's value.
+func DescribeFlag(cmd *cobra.Command, flag string) []string {
+	var descs []string
+	for _, r := range byCmd[cmd] {
+		for _, n := range r.names() {
+			if n == flag {
+				descs = append(descs, r.describe(flag))
+				break
+			}
+		}
+	}
+	return descs
+}
+
+var byCmd = map[*cobra.Command][]Rule{}
+
+// MutuallyExclusive reports an error if more than one of flags is set.
+func MutuallyExclusive(flags ...string) Rule { return mutexRule{flags} }
+
+type mutexRule struct{ flags []string }
+
+func (r mutexRule) names() []string { return r.flags }
+
+func (r mutexRule) describe(flag string) string {
+	others := make([]string, 0, len(r.flags)-1)
+	for _, n := range r.flags {
+		if n != flag {
+			others = append(others, n)
+		}
+	}
+	return "mutually exclusive with " + quoteAll(others)
+}
+
+func (r mutexRule) check(cmd *cobra.Command, envPrefix string) error {
+	var set []string
+	for _, n := range r.flags {
+		if f := cmd.Flags().Lookup(n); f != nil && f.Changed {
+			set = append(set, n)
+		}
+	}
+	if len(set) > 1 {
+		return fmt.Errorf("flags %s cannot be used together", describeAll(cmd, set, envPrefix))
+	}
+	return nil
+}
+
+// Requires reports an error if flag is set but needs is not.
+func Requires(flag, needs string) Rule { return requiresRule{flag, needs} }
+
+type requiresRule struct{ flag, needs string }
+
+func (r requiresRule) names() []string { return []string{r.flag, r.needs} }
+
+func (r requiresRule) describe(flag string) string {
+	if flag == r.flag {
+		return "requires --" + r.needs
+	}
+	return "required by --" + r.flag
+}
+
+func (r requiresRule) check(cmd *cobra.Command, envPrefix string) error {
+	f := cmd.Flags().Lookup(r.flag)
+	if f == nil || !f.Changed {
+		return nil
+	}
+	n := cmd.Flags().Lookup(r.needs)
+	if n == nil || !n.Changed {
+		return fmt.Errorf("flag %s requires --%s", describeAll(cmd, []string{r.flag}, envPrefix), r.needs)
+	}
+	return nil
+}
+
+// RequiresOneOf reports an error if flag is set but none of anyOf are.
+func RequiresOneOf(flag string, anyOf []string) Rule { return requiresOneOfRule{flag, anyOf} }
+
+type requiresOneOfRule struct {
+	flag  string
+	anyOf []string
+}
+
+func (r requiresOneOfRule) names() []string { return append([]string{r.flag}, r.anyOf...) }
+
+func (r requiresOneOfRule) describe(flag string) string {
+	if flag == r.flag {
+		return "requires one of " + quoteAll(r.anyOf)
+	}
+	return "satisfies --" + r.flag + "'s requirement"
+}
+
+func (r requiresOneOfRule) check(cmd *cobra.Command, envPrefix string) error {
+	f := cmd.Flags().Lookup(r.flag)
+	if f == nil || !f.Changed {
+		return nil
+	}
+	for _, n := range r.anyOf {
+		if nf := cmd.Flags().Lookup(n); nf != nil && nf.Changed {
+			return nil
+		}
+	}
+	return fmt.Errorf("flag %s requires one of %s", describeAll(cmd, []string{r.flag}, envPrefix), quoteAll(r.anyOf))
+}
+
+// Forbids reports an error if flag is set alongside any of others.
+func Forbids(flag string, others ...string) Rule { return forbidsRule{flag, others} }
+
+type forbidsRule struct {
+	flag    string
+	forbids []string
+}
+
+func (r forbidsRule) names() []string { return append([]string{r.flag}, r.forbids...) }
+
+func (r forbidsRule) describe(flag string) string {
+	if flag == r.flag {
+		return "overrides " + quoteAll(r.forbids)
+	}
+	return "overridden by --" + r.flag
+}
+
+func (r forbidsRule) check(cmd *cobra.Command, envPrefix string) error {
+	f := cmd.Flags().Lookup(r.flag)
+	if f == nil || !f.Changed {
+		return nil
+	}
+	var set []string
+	for _, n := range r.forbids {
+		if nf := cmd.Flags().Lookup(n); nf != nil && nf.Changed {
+			set = append(set, n)
+		}
+	}
+	if len(set) > 0 {
+		return fmt.Errorf("flag %s overrides %s; they cannot be used together", describeAll(cmd, []string{r.flag}, envPrefix), describeAll(cmd, set, envPrefix))
+	}
+	return nil
+}
+
+// Exclusive reports an error if flag is set alongside any other flag at all,
+// e.g. --help or --passthrough.
+func Exclusive(flag string) Rule { return exclusiveRule{flag} }
+
+type exclusiveRule struct{ flag string }
+
+func (r exclusiveRule) names() []string { return []string{r.flag} }
+
+func (r exclusiveRule) describe(flag string) string {
+	return "cannot be combined with any other flag"
+}
+
+func (r exclusiveRule) check(cmd *cobra.Command, envPrefix string) error {
+	f := cmd.Flags().Lookup(r.flag)
+	if f == nil || !f.Changed {
+		return nil
+	}
+	var others []string
+	cmd.Flags().Visit(func(fl *pflag.Flag) {
+		if fl.Name != r.flag {
+			others = append(others, fl.Name)
+		}
+	})
+	if len(others) > 0 {
+		return fmt.Errorf("flag %s can't be used with other flags, saw %s", describeAll(cmd, []string{r.flag}, envPrefix), describeAll(cmd, others, envPrefix))
+	}
+	return nil
+}
+
+// WhenEquals begins a value-conditioned rule, e.g.
+// WhenEquals("metricformat", "statsd").Forbids("nobreaker").
+func WhenEquals(flag, value string) Condition { return Condition{flag, value} }
+
+// Condition is the left-hand side of a value-conditioned rule.
+type Condition struct{ flag, value string }
+
+// Forbids reports an error if other is set while the condition holds.
+func (c Condition) Forbids(other string) Rule { return condRule{c, other} }
+
+type condRule struct {
+	cond    Condition
+	forbids string
+}
+
+func (r condRule) names() []string { return []string{r.cond.flag, r.forbids} }
+
+func (r condRule) describe(flag string) string {
+	if flag == r.cond.flag {
+		return fmt.Sprintf("when =%s, forbids --%s", r.cond.value, r.forbids)
+	}
+	return fmt.Sprintf("forbidden when --%s=%s", r.cond.flag, r.cond.value)
+}
+
+func (r condRule) check(cmd *cobra.Command, envPrefix string) error {
+	f := cmd.Flags().Lookup(r.cond.flag)
+	if f == nil || f.Value.String() != r.cond.value {
+		return nil
+	}
+	o := cmd.Flags().Lookup(r.forbids)
+	if o != nil && o.Changed {
+		return fmt.Errorf("--%s=%s cannot be used with %s", r.cond.flag, r.cond.value, describeAll(cmd, []string{r.forbids}, envPrefix))
+	}
+	return nil
+}
+
+func quoteAll(names []string) string {
+	q := make([]string, len(names))
+	for i, n := range names {
+		q[i] = "--" + n
+	}
+	return strings.Join(q, ", ")
+}
+
+// describeAll renders names as "--flag (source)" for each, joined with ", ".
+func describeAll(cmd *cobra.Command, names []string, envPrefix string) string {
+	parts := make([]string, len(names))
+	for i, n := range names {
+		parts[i] = fmt.Sprintf("--%s (%s)", n, SourceOf(cmd, n, envPrefix))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// SourceOf guesses where a flag's effective value came from: the command
+// line, an environment variable, or a config file. Cobra/pflag don't track
+// this distinction themselves, so this is inferred from os.Args and the
+// environment at the time it's called. cmd must be the command cobra is
+// actually executing (or otherwise have had its persistent flags merged into
+// cmd.Flags()) for name to resolve; a caller that already has the *pflag.Flag
+// in hand, e.g. from VisitAll on a FlagSet fetched directly such as
+// cmd.PersistentFlags(), should call SourceOfFlag instead.
+func SourceOf(cmd *cobra.Command, name, envPrefix string) string {
+	f := cmd.Flags().Lookup(name)
+	if f == nil {
+		return "default"
+	}
+	return SourceOfFlag(f, envPrefix)
+}
+
+// SourceOfFlag is SourceOf given the flag itself rather than its name and
+// owning command, for callers holding a *pflag.Flag from a FlagSet that
+// isn't (or might not be) cmd.Flags() - see SourceOf.
+func SourceOfFlag(f *pflag.Flag, envPrefix string) string {
+	if !f.Changed {
+		return "default"
+	}
+	needle := "--" + f.Name
+	for _, a := range os.Args {
+		if a == needle || strings.HasPrefix(a, needle+"=") {
+			return "flag"
+		}
+		if f.Shorthand != "" && a == "-"+f.Shorthand {
+			return "flag"
+		}
+	}
+	if envPrefix != "" {
+		envVar := envPrefix + "_" + strings.ToUpper(strings.ReplaceAll(f.Name, "-", "_"))
+		if _, ok := os.LookupEnv(envVar); ok {
+			return "env"
+		}
+	}
+	return "config"
+}
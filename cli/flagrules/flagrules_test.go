@@ -0,0 +1,131 @@
+package flagrules
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func newTestCmd() *cobra.Command {
+	cmd := &cobra.Command{Use: "test"}
+	cmd.Flags().String("a", "", "")
+	cmd.Flags().String("b", "", "")
+	cmd.Flags().String("c", "", "")
+	return cmd
+}
+
+func TestMutuallyExclusive(t *testing.T) {
+	cmd := newTestCmd()
+	Register(cmd, "", MutuallyExclusive("a", "b"))
+
+	if err := Validate(cmd, ""); err != nil {
+		t.Fatalf("expected no error with nothing set, got %v", err)
+	}
+
+	cmd.Flags().Set("a", "1")
+	if err := Validate(cmd, ""); err != nil {
+		t.Fatalf("expected no error with only one flag set, got %v", err)
+	}
+
+	cmd.Flags().Set("b", "1")
+	if err := Validate(cmd, ""); err == nil {
+		t.Fatal("expected an error with both flags set")
+	}
+}
+
+func TestRequires(t *testing.T) {
+	cmd := newTestCmd()
+	Register(cmd, "", Requires("a", "b"))
+
+	if err := Validate(cmd, ""); err != nil {
+		t.Fatalf("expected no error when a is unset, got %v", err)
+	}
+
+	cmd.Flags().Set("a", "1")
+	if err := Validate(cmd, ""); err == nil {
+		t.Fatal("expected an error when a is set without b")
+	}
+
+	cmd.Flags().Set("b", "1")
+	if err := Validate(cmd, ""); err != nil {
+		t.Fatalf("expected no error once b is also set, got %v", err)
+	}
+}
+
+func TestRequiresOneOf(t *testing.T) {
+	cmd := newTestCmd()
+	Register(cmd, "", RequiresOneOf("a", []string{"b", "c"}))
+
+	cmd.Flags().Set("a", "1")
+	if err := Validate(cmd, ""); err == nil {
+		t.Fatal("expected an error when neither b nor c is set")
+	}
+
+	cmd.Flags().Set("c", "1")
+	if err := Validate(cmd, ""); err != nil {
+		t.Fatalf("expected no error once c is set, got %v", err)
+	}
+}
+
+func TestForbids(t *testing.T) {
+	cmd := newTestCmd()
+	Register(cmd, "", Forbids("a", "b", "c"))
+
+	cmd.Flags().Set("b", "1")
+	if err := Validate(cmd, ""); err != nil {
+		t.Fatalf("expected no error when a is unset, got %v", err)
+	}
+
+	cmd.Flags().Set("a", "1")
+	if err := Validate(cmd, ""); err == nil {
+		t.Fatal("expected an error when a and b are both set")
+	}
+}
+
+func TestExclusive(t *testing.T) {
+	cmd := newTestCmd()
+	Register(cmd, "", Exclusive("a"))
+
+	cmd.Flags().Set("a", "1")
+	if err := Validate(cmd, ""); err != nil {
+		t.Fatalf("expected no error when a is set alone, got %v", err)
+	}
+
+	cmd.Flags().Set("b", "1")
+	if err := Validate(cmd, ""); err == nil {
+		t.Fatal("expected an error when a is combined with another flag")
+	}
+}
+
+func TestWhenEqualsForbids(t *testing.T) {
+	cmd := newTestCmd()
+	Register(cmd, "", WhenEquals("a", "x").Forbids("b"))
+
+	cmd.Flags().Set("a", "y")
+	cmd.Flags().Set("b", "1")
+	if err := Validate(cmd, ""); err != nil {
+		t.Fatalf("expected no error when condition doesn't hold, got %v", err)
+	}
+
+	cmd.Flags().Set("a", "x")
+	if err := Validate(cmd, ""); err == nil {
+		t.Fatal("expected an error when condition holds and b is set")
+	}
+}
+
+func TestDescribeFlag(t *testing.T) {
+	cmd := newTestCmd()
+	Register(cmd, "", MutuallyExclusive("a", "b"))
+
+	descs := DescribeFlag(cmd, "a")
+	if len(descs) != 1 {
+		t.Fatalf("expected one rule description for a, got %v", descs)
+	}
+}
+
+func TestSourceOf(t *testing.T) {
+	cmd := newTestCmd()
+	if got := SourceOf(cmd, "a", ""); got != "default" {
+		t.Fatalf("expected default source for an unset flag, got %q", got)
+	}
+}
@@ -0,0 +1,81 @@
+// Package history locates and manages on-disk scope session history.
+package history
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Session describes a single scoped run recorded on disk.
+type Session struct {
+	ID   int
+	Path string
+}
+
+// SessionList is an ordered collection of sessions, oldest first.
+type SessionList []Session
+
+// Last returns the last n sessions, or all of them if there are fewer than n.
+func (sl SessionList) Last(n int) SessionList {
+	if len(sl) <= n {
+		return sl
+	}
+	return sl[len(sl)-n:]
+}
+
+// ID returns the session matching id, or an empty list if none match.
+func (sl SessionList) ID(id int) SessionList {
+	for _, s := range sl {
+		if s.ID == id {
+			return SessionList{s}
+		}
+	}
+	return nil
+}
+
+// Remove deletes the on-disk directories for every session in the list.
+func (sl SessionList) Remove() {
+	for _, s := range sl {
+		os.RemoveAll(s.Path)
+	}
+}
+
+// Store locates scope session history. Its default implementation reads a
+// directory on disk, but callers should depend on the interface so tests can
+// substitute a fake store rather than reading real files.
+type Store interface {
+	// Sessions returns every known session, ordered oldest first.
+	Sessions() SessionList
+}
+
+// fsStore is the filesystem-backed Store used outside of tests.
+type fsStore struct {
+	dir string
+}
+
+// NewFsStore returns a Store that reads sessions from dir, a scope history
+// directory (normally ~/.scope/history).
+func NewFsStore(dir string) Store {
+	return fsStore{dir: dir}
+}
+
+func (s fsStore) Sessions() SessionList {
+	entries, err := ioutil.ReadDir(s.dir)
+	if err != nil {
+		return nil
+	}
+	var sl SessionList
+	for _, e := range entries {
+		id, err := strconv.Atoi(strings.TrimSuffix(e.Name(), filepath.Ext(e.Name())))
+		if err != nil {
+			continue
+		}
+		sl = append(sl, Session{ID: id, Path: filepath.Join(s.dir, e.Name())})
+	}
+	sort.Slice(sl, func(i, j int) bool { return sl[i].ID < sl[j].ID })
+	return sl
+}
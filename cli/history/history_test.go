@@ -0,0 +1,62 @@
+package history
+
+import (
+	"os"
+	"testing"
+)
+
+func TestSessionListLast(t *testing.T) {
+	sl := SessionList{{ID: 1}, {ID: 2}, {ID: 3}}
+
+	if got := sl.Last(2); len(got) != 2 || got[0].ID != 2 || got[1].ID != 3 {
+		t.Fatalf("Last(2) = %v, want sessions 2 and 3", got)
+	}
+
+	if got := sl.Last(10); len(got) != 3 {
+		t.Fatalf("Last(10) = %v, want all 3 sessions", got)
+	}
+}
+
+func TestSessionListID(t *testing.T) {
+	sl := SessionList{{ID: 1}, {ID: 2}}
+
+	if got := sl.ID(2); len(got) != 1 || got[0].ID != 2 {
+		t.Fatalf("ID(2) = %v, want session 2", got)
+	}
+
+	if got := sl.ID(99); got != nil {
+		t.Fatalf("ID(99) = %v, want nil", got)
+	}
+}
+
+func TestSessionListRemove(t *testing.T) {
+	dir := t.TempDir()
+	sl := SessionList{{ID: 1, Path: dir}}
+
+	sl.Remove()
+
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Fatalf("expected %s to be removed, stat err = %v", dir, err)
+	}
+}
+
+func TestFsStoreSessions(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"1.json", "3.json", "2.json", "not-a-session.txt"} {
+		if err := os.WriteFile(dir+"/"+name, nil, 0o644); err != nil {
+			t.Fatalf("setup: %v", err)
+		}
+	}
+
+	store := NewFsStore(dir)
+	sessions := store.Sessions()
+
+	if len(sessions) != 3 {
+		t.Fatalf("Sessions() returned %d entries, want 3 (non-numeric names ignored): %v", len(sessions), sessions)
+	}
+	for i, want := range []int{1, 2, 3} {
+		if sessions[i].ID != want {
+			t.Fatalf("Sessions()[%d].ID = %d, want %d (expected oldest-first order)", i, sessions[i].ID, want)
+		}
+	}
+}
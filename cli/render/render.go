@@ -0,0 +1,59 @@
+// Package render formats command results for scope's --output text|json|yaml
+// modes, so wrapper scripts can parse scope's output instead of scraping
+// human-oriented text.
+package render
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Format selects how Object writes a value.
+type Format string
+
+// Supported output formats; Text is the default, human-oriented format.
+const (
+	Text Format = "text"
+	JSON Format = "json"
+	YAML Format = "yaml"
+)
+
+// ErrorResult is the machine-readable shape of an error, used by
+// cmd.helpErrAndExit when the output format isn't Text.
+type ErrorResult struct {
+	Error string `json:"error" yaml:"error"`
+}
+
+// PromptResult is the machine-readable shape of a yes/no prompt's outcome,
+// used by cmd.promptClean when the output format isn't Text.
+type PromptResult struct {
+	Removed bool `json:"removed" yaml:"removed"`
+}
+
+// Object writes v to w in the given format. In Text mode, v is written with
+// its Stringer implementation if it has one, else with "%v".
+func Object(w io.Writer, format Format, v interface{}) error {
+	switch format {
+	case JSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	case YAML:
+		b, err := yaml.Marshal(v)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(b)
+		return err
+	default:
+		if s, ok := v.(fmt.Stringer); ok {
+			_, err := fmt.Fprintln(w, s.String())
+			return err
+		}
+		_, err := fmt.Fprintf(w, "%v\n", v)
+		return err
+	}
+}
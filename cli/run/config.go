@@ -0,0 +1,63 @@
+// Package run holds the configuration and execution logic for scoping a command.
+package run
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/criblio/scope/run/metricfmt"
+)
+
+// Config holds all of the settings that control how ldscope is invoked for a
+// single scoped run. Its fields are populated directly by cmd's flag
+// definitions (see cmd.runCmdFlags and cmd.metricAndEventDestFlags).
+type Config struct {
+	Passthrough    bool
+	Verbosity      int
+	Payloads       bool
+	Loglevel       string
+	LibraryPath    string
+	UserConfig     string
+	CriblDest      string
+	MetricsFormat  string
+	MetricsDest    string
+	EventsDest     string
+	NoBreaker      bool
+	AuthToken      string
+	MetricHeaders  map[string]string
+	MetricResource map[string]string
+
+	// MetricTransport is resolved by ValidateMetricFormat from MetricsFormat,
+	// telling Runner how MetricsDest should be dialed (e.g. otlp-grpc needs
+	// gRPC framing, not a raw TLS socket).
+	MetricTransport metricfmt.Transport
+}
+
+// ValidateMetricFormat checks that rc.MetricsFormat names a format registered
+// in formats, and that --metricheaders/--metricresource are only set when
+// that format is an OTLP one. It also resolves rc.MetricTransport from the
+// format, and - if MetricsDest was given without one - fills in the format's
+// default port.
+func (rc *Config) ValidateMetricFormat(formats *metricfmt.Registry) error {
+	f, ok := formats.Lookup(rc.MetricsFormat)
+	if !ok {
+		return fmt.Errorf("unknown --metricformat %q; must be one of %s", rc.MetricsFormat, formats.Usage())
+	}
+	if !f.OTLP && (len(rc.MetricHeaders) > 0 || len(rc.MetricResource) > 0) {
+		return fmt.Errorf("--metricheaders/--metricresource only apply to OTLP formats (otlp-http|otlp-grpc), not %q", rc.MetricsFormat)
+	}
+	if f.Validate != nil {
+		if err := f.Validate(rc.MetricsDest); err != nil {
+			return fmt.Errorf("invalid --metricdest for --metricformat %s: %w", rc.MetricsFormat, err)
+		}
+	}
+	hostPort := rc.MetricsDest
+	if i := strings.Index(hostPort, "://"); i >= 0 {
+		hostPort = hostPort[i+len("://"):]
+	}
+	if hostPort != "" && !strings.Contains(hostPort, ":") {
+		rc.MetricsDest = fmt.Sprintf("%s:%d", rc.MetricsDest, f.DefaultPort)
+	}
+	rc.MetricTransport = f.Transport
+	return nil
+}
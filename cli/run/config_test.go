@@ -0,0 +1,65 @@
+package run
+
+import (
+	"testing"
+
+	"github.com/criblio/scope/run/metricfmt"
+)
+
+func TestValidateMetricFormatUnknown(t *testing.T) {
+	rc := &Config{MetricsFormat: "bogus"}
+	if err := rc.ValidateMetricFormat(metricfmt.NewRegistry()); err == nil {
+		t.Fatal("expected an error for an unknown --metricformat")
+	}
+}
+
+func TestValidateMetricFormatRejectsOTLPFlagsOnNonOTLPFormat(t *testing.T) {
+	rc := &Config{
+		MetricsFormat: "ndjson",
+		MetricHeaders: map[string]string{"x": "y"},
+	}
+	if err := rc.ValidateMetricFormat(metricfmt.NewRegistry()); err == nil {
+		t.Fatal("expected an error when --metricheaders is set with a non-OTLP format")
+	}
+}
+
+func TestValidateMetricFormatAllowsOTLPFlagsOnOTLPFormat(t *testing.T) {
+	rc := &Config{
+		MetricsFormat:  "otlp-http",
+		MetricHeaders:  map[string]string{"x": "y"},
+		MetricResource: map[string]string{"service.name": "myapp"},
+	}
+	if err := rc.ValidateMetricFormat(metricfmt.NewRegistry()); err != nil {
+		t.Fatalf("expected no error for an OTLP format, got %v", err)
+	}
+}
+
+func TestValidateMetricFormatResolvesTransport(t *testing.T) {
+	rc := &Config{MetricsFormat: "otlp-grpc"}
+	if err := rc.ValidateMetricFormat(metricfmt.NewRegistry()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rc.MetricTransport != metricfmt.TransportGRPC {
+		t.Fatalf("MetricTransport = %q, want %q", rc.MetricTransport, metricfmt.TransportGRPC)
+	}
+}
+
+func TestValidateMetricFormatFillsDefaultPort(t *testing.T) {
+	rc := &Config{MetricsFormat: "statsd", MetricsDest: "collector.example.com"}
+	if err := rc.ValidateMetricFormat(metricfmt.NewRegistry()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := "collector.example.com:8125"; rc.MetricsDest != want {
+		t.Fatalf("MetricsDest = %q, want %q", rc.MetricsDest, want)
+	}
+}
+
+func TestValidateMetricFormatLeavesExplicitPortAlone(t *testing.T) {
+	rc := &Config{MetricsFormat: "statsd", MetricsDest: "collector.example.com:9999"}
+	if err := rc.ValidateMetricFormat(metricfmt.NewRegistry()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := "collector.example.com:9999"; rc.MetricsDest != want {
+		t.Fatalf("MetricsDest = %q, want %q", rc.MetricsDest, want)
+	}
+}
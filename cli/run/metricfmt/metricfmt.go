@@ -0,0 +1,81 @@
+// Package metricfmt is a registry of pluggable metric output formats for
+// scope's --metricformat flag, so new formats can be added without touching
+// cmd's flag parsing or validation.
+package metricfmt
+
+import "strings"
+
+// Transport hints at how a format's destination should be dialed.
+type Transport string
+
+// Transports scope knows how to dial a destination over.
+const (
+	TransportTLS  Transport = "tls"
+	TransportHTTP Transport = "http"
+	TransportGRPC Transport = "grpc"
+)
+
+// Format describes one metric output format registered with a Registry.
+type Format struct {
+	// Name is the --metricformat value that selects this format.
+	Name string
+	// DefaultPort is used when a destination is given without one.
+	DefaultPort int
+	// Transport is how --metricdest should be dialed for this format.
+	Transport Transport
+	// OTLP reports whether this format accepts --metricheaders and
+	// --metricresource.
+	OTLP bool
+	// Validate checks that dest is a usable destination for this format. A
+	// nil Validate accepts anything.
+	Validate func(dest string) error
+}
+
+// Registry holds every metric output format scope knows about, keyed by name.
+type Registry struct {
+	formats map[string]Format
+	order   []string
+}
+
+// NewRegistry returns a Registry pre-populated with scope's built-in formats:
+// statsd, ndjson, otlp-http, otlp-grpc, and prometheus-remote-write.
+func NewRegistry() *Registry {
+	r := &Registry{formats: make(map[string]Format)}
+	for _, f := range builtins {
+		r.Register(f)
+	}
+	return r
+}
+
+// Register adds f to the registry, replacing any existing format of the same name.
+func (r *Registry) Register(f Format) {
+	if _, exists := r.formats[f.Name]; !exists {
+		r.order = append(r.order, f.Name)
+	}
+	r.formats[f.Name] = f
+}
+
+// Lookup returns the format named name, and whether it was found.
+func (r *Registry) Lookup(name string) (Format, bool) {
+	f, ok := r.formats[name]
+	return f, ok
+}
+
+// Names returns every registered format name, in registration order.
+func (r *Registry) Names() []string {
+	return append([]string(nil), r.order...)
+}
+
+// Usage renders a "name|name|name" list of registered formats for use in a
+// flag's usage string.
+func (r *Registry) Usage() string {
+	return strings.Join(r.order, "|")
+}
+
+var builtins = []Format{
+	{Name: "statsd", DefaultPort: 8125, Transport: TransportTLS},
+	{Name: "ndjson", DefaultPort: 10070, Transport: TransportTLS},
+	{Name: "otlp-http", DefaultPort: 4318, Transport: TransportHTTP, OTLP: true},
+	{Name: "otlp-grpc", DefaultPort: 4317, Transport: TransportGRPC, OTLP: true},
+	{Name: "prometheus-remote-write", DefaultPort: 9090, Transport: TransportHTTP},
+}
@@ -0,0 +1,61 @@
+package metricfmt
+
+import "testing"
+
+func TestNewRegistryHasBuiltins(t *testing.T) {
+	r := NewRegistry()
+
+	for _, name := range []string{"statsd", "ndjson", "otlp-http", "otlp-grpc", "prometheus-remote-write"} {
+		if _, ok := r.Lookup(name); !ok {
+			t.Errorf("expected built-in format %q to be registered", name)
+		}
+	}
+}
+
+func TestOTLPFormatsAreMarked(t *testing.T) {
+	r := NewRegistry()
+
+	for name, wantOTLP := range map[string]bool{
+		"statsd":    false,
+		"ndjson":    false,
+		"otlp-http": true,
+		"otlp-grpc": true,
+	} {
+		f, ok := r.Lookup(name)
+		if !ok {
+			t.Fatalf("format %q not found", name)
+		}
+		if f.OTLP != wantOTLP {
+			t.Errorf("%q.OTLP = %v, want %v", name, f.OTLP, wantOTLP)
+		}
+	}
+}
+
+func TestRegisterAddsNewFormat(t *testing.T) {
+	r := NewRegistry()
+	r.Register(Format{Name: "carbon", DefaultPort: 2003, Transport: TransportTLS})
+
+	if _, ok := r.Lookup("carbon"); !ok {
+		t.Fatal("expected custom format to be registered")
+	}
+}
+
+func TestUsageListsAllNames(t *testing.T) {
+	r := NewRegistry()
+	usage := r.Usage()
+
+	for _, name := range r.Names() {
+		if !contains(usage, name) {
+			t.Errorf("Usage() %q missing format %q", usage, name)
+		}
+	}
+}
+
+func contains(haystack, needle string) bool {
+	for i := 0; i+len(needle) <= len(haystack); i++ {
+		if haystack[i:i+len(needle)] == needle {
+			return true
+		}
+	}
+	return false
+}
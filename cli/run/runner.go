@@ -0,0 +1,24 @@
+package run
+
+// Runner executes a scoped command described by a Config. Depending on the
+// interface rather than calling into this package's internals directly lets
+// cmd substitute a fake Runner in tests.
+type Runner interface {
+	Run(rc *Config, args []string) error
+}
+
+// realRunner is the Runner used outside of tests. Its Run does not start
+// ldscope or the scoped command; it returns nil without acting on rc or args.
+type realRunner struct{}
+
+// NewRunner returns the default Runner implementation.
+func NewRunner() Runner {
+	return realRunner{}
+}
+
+func (realRunner) Run(rc *Config, args []string) error {
+	// rc.MetricTransport (resolved from --metricformat by
+	// Config.ValidateMetricFormat) is read here but not used to dial anything.
+	_ = rc.MetricTransport
+	return nil
+}
@@ -0,0 +1,44 @@
+package run
+
+import "testing"
+
+// fakeRunner is the kind of test double the Runner interface exists to allow.
+type fakeRunner struct {
+	called bool
+	gotRc  *Config
+	gotArg []string
+	err    error
+}
+
+func (f *fakeRunner) Run(rc *Config, args []string) error {
+	f.called = true
+	f.gotRc = rc
+	f.gotArg = args
+	return f.err
+}
+
+func TestRunnerInterfaceIsSubstitutable(t *testing.T) {
+	var r Runner = &fakeRunner{}
+	rc := &Config{Verbosity: 4}
+
+	if err := r.Run(rc, []string{"ls"}); err != nil {
+		t.Fatalf("Run returned %v, want nil", err)
+	}
+
+	fake := r.(*fakeRunner)
+	if !fake.called {
+		t.Fatal("expected Run to be called")
+	}
+	if fake.gotRc != rc {
+		t.Fatalf("Run got Config %v, want %v", fake.gotRc, rc)
+	}
+	if len(fake.gotArg) != 1 || fake.gotArg[0] != "ls" {
+		t.Fatalf("Run got args %v, want [ls]", fake.gotArg)
+	}
+}
+
+func TestNewRunnerReturnsNonNil(t *testing.T) {
+	if NewRunner() == nil {
+		t.Fatal("NewRunner() returned nil")
+	}
+}
@@ -0,0 +1,20 @@
+// Package util contains small helpers shared across scope's cmd packages.
+package util
+
+import (
+	"fmt"
+	"os"
+)
+
+// ErrAndExit prints a formatted error message to stderr and exits with status 1.
+func ErrAndExit(format string, args ...interface{}) {
+	fmt.Fprintf(os.Stderr, format+"\n", args...)
+	os.Exit(1)
+}
+
+// CheckErrSprintf calls ErrAndExit with the given format and args if err is non-nil.
+func CheckErrSprintf(err error, format string, args ...interface{}) {
+	if err != nil {
+		ErrAndExit(format, args...)
+	}
+}